@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cespare/xxhash/v2"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// defaultBoundedLoadOverflow is the default value of `c` in consistent
+// hashing with bounded loads: an activator may carry up to
+// ceil(avgLoad*c) revisions before the ring walk skips it in favor of its
+// successor. It is used when the controller config does not set one.
+const defaultBoundedLoadOverflow = 1.25
+
+// ringEntry is a single point on the consistent-hashing ring.
+type ringEntry struct {
+	hash uint64
+	ip   string
+}
+
+// activatorRing builds a sorted hash ring from the given activator IPs.
+// Using xxhash of the IP (rather than the IP string order) spreads ring
+// positions uniformly, which is what keeps per-activator load balanced.
+func activatorRing(ips sets.String) []ringEntry {
+	ring := make([]ringEntry, 0, len(ips))
+	for _, ip := range ips.List() {
+		ring = append(ring, ringEntry{hash: xxhash.Sum64String(ip), ip: ip})
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+	return ring
+}
+
+// chooseBoundedLoadSubset selects up to `n` distinct activator IPs for
+// `target` by walking `ring` clockwise from target's hash, implementing
+// consistent hashing with bounded loads (Mirrokni, Thorup & Zadimoghaddam).
+// `loads` holds the number of revisions each activator IP currently backs
+// and is updated in place with the new selection. An activator is skipped
+// in favor of its successor once its load would exceed ceil(avgLoad*overflow).
+//
+// The bounded walk can legitimately stop short of `n` if every candidate on
+// the ring is already at the load bound (e.g. `n` close to len(ring) leaves
+// little slack). Rather than ignoring the bound outright in that case, the
+// second return value reports whether it had to be relaxed, and the bound
+// itself is grown by one load unit at a time -- the smallest relaxation that
+// guarantees progress -- until `n` addresses (or, if `n` exceeds len(ring),
+// every ring entry) are selected. Callers should
+// treat relaxed=true as a signal worth recording (it means the ring is
+// genuinely overloaded relative to `n`), not as routine behavior.
+func chooseBoundedLoadSubset(ring []ringEntry, target string, n int, loads map[string]int, avgLoad, overflow float64) (sets.String, bool) {
+	selected := sets.NewString()
+	if len(ring) == 0 || n == 0 {
+		return selected, false
+	}
+
+	maxLoad := int(math.Ceil(avgLoad * overflow))
+	if maxLoad < 1 {
+		maxLoad = 1
+	}
+
+	targetHash := xxhash.Sum64String(target)
+	start := sort.Search(len(ring), func(i int) bool { return ring[i].hash >= targetHash }) % len(ring)
+
+	fill := func(bound int) {
+		for i := 0; selected.Len() < n && i < len(ring); i++ {
+			e := ring[(start+i)%len(ring)]
+			if !selected.Has(e.ip) && loads[e.ip] < bound {
+				selected.Insert(e.ip)
+				loads[e.ip]++
+			}
+		}
+	}
+
+	fill(maxLoad)
+	relaxed := false
+	// Once the bound exceeds the highest load on the ring, fill admits every
+	// remaining candidate, so the loop ends when `n` addresses or every ring
+	// entry are selected.
+	for selected.Len() < n && selected.Len() < len(ring) {
+		relaxed = true
+		maxLoad++
+		fill(maxLoad)
+	}
+	return selected, relaxed
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestChooseBoundedLoadSubsetStableAcrossUnrelatedChurn(t *testing.T) {
+	ips := sets.NewString()
+	for i := 0; i < 10; i++ {
+		ips.Insert(fmt.Sprintf("10.0.0.%d", i))
+	}
+	ring := activatorRing(ips)
+	loads := map[string]int{}
+
+	first, relaxed := chooseBoundedLoadSubset(ring, "rev-1", 3, loads, 0, defaultBoundedLoadOverflow)
+	if relaxed {
+		t.Fatal("unexpected bound relaxation with an empty load table")
+	}
+
+	// Release rev-1's load (as releaseActivatorLoad would) and remove an
+	// activator IP that rev-1 did not select; rev-1's own selection should be
+	// unaffected since the ring only changes around the removed IP.
+	for ip := range first {
+		loads[ip]--
+	}
+	unrelated := ips.List()[0]
+	for _, ip := range first.List() {
+		if ip == unrelated {
+			t.Skip("chosen activator coincides with the removed one; retry with a different fixture")
+		}
+	}
+	ips.Delete(unrelated)
+	ring = activatorRing(ips)
+
+	second, _ := chooseBoundedLoadSubset(ring, "rev-1", 3, loads, 0, defaultBoundedLoadOverflow)
+	if !first.Equal(second) {
+		t.Errorf("selection changed after removing an unrelated activator: got %v, want %v", second.List(), first.List())
+	}
+}
+
+func TestChooseBoundedLoadSubsetRespectsBound(t *testing.T) {
+	ips := sets.NewString()
+	for i := 0; i < 5; i++ {
+		ips.Insert(fmt.Sprintf("10.0.0.%d", i))
+	}
+	ring := activatorRing(ips)
+	loads := map[string]int{}
+	const overflow = 1.25
+	const n = 2
+
+	for rev := 0; rev < 20; rev++ {
+		avgLoad := float64(rev) * float64(n) / float64(len(ips))
+		maxLoad := int(math.Ceil(avgLoad * overflow))
+		if maxLoad < 1 {
+			maxLoad = 1
+		}
+		selection, relaxed := chooseBoundedLoadSubset(ring, fmt.Sprintf("rev-%d", rev), n, loads, avgLoad, overflow)
+		if selection.Len() != n {
+			t.Fatalf("rev-%d: got %d addresses, want %d", rev, selection.Len(), n)
+		}
+		if !relaxed {
+			for ip := range selection {
+				if loads[ip] > maxLoad {
+					t.Errorf("rev-%d: activator %s load %d exceeds bound %d", rev, ip, loads[ip], maxLoad)
+				}
+			}
+		}
+	}
+}
+
+func TestChooseBoundedLoadSubsetRelaxesWhenRingIsSaturated(t *testing.T) {
+	ips := sets.NewString("10.0.0.0", "10.0.0.1", "10.0.0.2")
+	ring := activatorRing(ips)
+	loads := map[string]int{"10.0.0.0": 100, "10.0.0.1": 100, "10.0.0.2": 100}
+
+	selection, relaxed := chooseBoundedLoadSubset(ring, "rev-1", 3, loads, 0, defaultBoundedLoadOverflow)
+	if selection.Len() != 3 {
+		t.Fatalf("got %d addresses, want 3", selection.Len())
+	}
+	if !relaxed {
+		t.Error("expected relaxed=true when every activator is already saturated")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"context"
+
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
+	endpointsliceinformer "knative.dev/pkg/client/injection/kube/informers/discovery/v1/endpointslice"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	sksinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/serverlessservice"
+	sksreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/serverlessservice"
+)
+
+// Features toggles the optional behaviors ReconcileKind can run. It is read
+// once at controller construction time; the caller (cmd/controller) is
+// responsible for sourcing it from whatever config mechanism the running
+// binary uses (a ConfigMap, flags, etc.) and for restarting the controller
+// process if it needs to change, since reconciler does not currently support
+// hot-reloading these flags.
+type Features struct {
+	// EnableEndpointSlices switches ReconcileKind's public-endpoints
+	// sub-reconciler from programming a single v1.Endpoints object to
+	// programming discoveryv1.EndpointSlices.
+	EnableEndpointSlices bool
+
+	// EnableBoundedLoadHashing switches activator subset selection from the
+	// legacy independent-per-revision hash.ChooseSubset to consistent
+	// hashing with bounded loads.
+	EnableBoundedLoadHashing bool
+
+	// BoundedLoadOverflow is the `c` factor in consistent hashing with
+	// bounded loads. Zero means "use defaultBoundedLoadOverflow".
+	BoundedLoadOverflow float64
+}
+
+// NewController creates a new ServerlessService controller, wiring it up to
+// the informers it needs and gating its EndpointSlice and bounded-load
+// hashing paths from feats.
+func NewController(ctx context.Context, feats Features) *controller.Impl {
+	serviceInformer := serviceinformer.Get(ctx)
+	endpointsInformer := endpointsinformer.Get(ctx)
+	sksInformer := sksinformer.Get(ctx)
+
+	r := &reconciler{
+		kubeclient:               kubeclient.Get(ctx),
+		serviceLister:            serviceInformer.Lister(),
+		endpointsLister:          endpointsInformer.Lister(),
+		enableEndpointSlices:     feats.EnableEndpointSlices,
+		enableBoundedLoadHashing: feats.EnableBoundedLoadHashing,
+		boundedLoadOverflow:      feats.BoundedLoadOverflow,
+	}
+
+	impl := sksreconciler.NewImpl(ctx, r)
+	logger := logging.FromContext(ctx)
+
+	serviceInformer.Informer().AddEventHandler(controller.HandleAll(impl.EnqueueControllerOf))
+	endpointsInformer.Informer().AddEventHandler(controller.HandleAll(impl.EnqueueControllerOf))
+	if r.enableEndpointSlices {
+		esInformer := endpointsliceinformer.Get(ctx)
+		r.endpointSliceLister = esInformer.Lister()
+		esInformer.Informer().AddEventHandler(controller.HandleAll(impl.EnqueueControllerOf))
+	}
+	sksInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	logger.Infof("Set up ServerlessService controller: endpointSlices=%t boundedLoadHashing=%t",
+		r.enableEndpointSlices, r.enableBoundedLoadHashing)
+	return impl
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"fmt"
+	"testing"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/serving/pkg/reconciler/serverlessservice/resources"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestEndpointSliceReady(t *testing.T) {
+	cases := []struct {
+		name string
+		cond discoveryv1.EndpointConditions
+		want bool
+	}{
+		{"unset defaults to ready", discoveryv1.EndpointConditions{}, true},
+		{"explicitly ready", discoveryv1.EndpointConditions{Ready: boolPtr(true)}, true},
+		{"explicitly not ready", discoveryv1.EndpointConditions{Ready: boolPtr(false)}, false},
+		{"terminating excludes regardless of ready", discoveryv1.EndpointConditions{Ready: boolPtr(true), Terminating: boolPtr(true)}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := endpointSliceReady(c.cond); got != c.want {
+				t.Errorf("endpointSliceReady(%+v) = %t, want %t", c.cond, got, c.want)
+			}
+		})
+	}
+}
+
+func sliceWithAddrs(addrType discoveryv1.AddressType, readyAddrs []string, terminatingAddrs []string) *discoveryv1.EndpointSlice {
+	s := &discoveryv1.EndpointSlice{AddressType: addrType}
+	for _, a := range readyAddrs {
+		s.Endpoints = append(s.Endpoints, discoveryv1.Endpoint{Addresses: []string{a}})
+	}
+	for _, a := range terminatingAddrs {
+		s.Endpoints = append(s.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{a},
+			Conditions: discoveryv1.EndpointConditions{Terminating: boolPtr(true)},
+		})
+	}
+	return s
+}
+
+func TestSubsetEndpointSlicesExcludesTerminating(t *testing.T) {
+	slices := []*discoveryv1.EndpointSlice{
+		sliceWithAddrs(discoveryv1.AddressTypeIPv4, []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.3"}),
+	}
+
+	got := subsetEndpointSlices(slices, "rev-1", 0, nil)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d endpoints, want 2 (terminating endpoint excluded)", len(got))
+	}
+	for _, ep := range got {
+		if ep.Addresses[0] == "10.0.0.3" {
+			t.Error("terminating endpoint was selected")
+		}
+	}
+}
+
+func TestSubsetEndpointSlicesZero(t *testing.T) {
+	slices := []*discoveryv1.EndpointSlice{
+		sliceWithAddrs(discoveryv1.AddressTypeIPv4, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, nil),
+	}
+	got := subsetEndpointSlices(slices, "rev-1", 0, nil)
+	if len(got) != 3 {
+		t.Errorf("n=0 should return all ready endpoints, got %d, want 3", len(got))
+	}
+}
+
+func TestSubsetEndpointSlicesStableForSameInputs(t *testing.T) {
+	slices := []*discoveryv1.EndpointSlice{
+		sliceWithAddrs(discoveryv1.AddressTypeIPv4, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}, nil),
+	}
+	first := subsetEndpointSlices(slices, "rev-1", 2, nil)
+	second := subsetEndpointSlices(slices, "rev-1", 2, nil)
+	if endpointAddrs(first).Len() != 2 {
+		t.Fatalf("got %d endpoints, want 2", endpointAddrs(first).Len())
+	}
+	if !endpointAddrs(first).Equal(endpointAddrs(second)) {
+		t.Error("subsetEndpointSlices is not stable across calls with identical inputs")
+	}
+}
+
+func TestSubsetEndpointSlicesPrefersZones(t *testing.T) {
+	zoneA, zoneB := "zone-a", "zone-b"
+	slice := &discoveryv1.EndpointSlice{
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Zone: &zoneA},
+			{Addresses: []string{"10.0.0.2"}, Zone: &zoneB},
+			{Addresses: []string{"10.0.0.3"}, Zone: &zoneB},
+		},
+	}
+
+	got := subsetEndpointSlices([]*discoveryv1.EndpointSlice{slice}, "rev-1", 1, sets.NewString(zoneA))
+
+	if len(got) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(got))
+	}
+	if got[0].Addresses[0] != "10.0.0.1" {
+		t.Errorf("got address %s, want the lone same-zone candidate 10.0.0.1", got[0].Addresses[0])
+	}
+}
+
+func TestSubsetEndpointSlicesFallsBackWhenZonesExhausted(t *testing.T) {
+	zoneA, zoneB := "zone-a", "zone-b"
+	slice := &discoveryv1.EndpointSlice{
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, Zone: &zoneA},
+			{Addresses: []string{"10.0.0.2"}, Zone: &zoneB},
+			{Addresses: []string{"10.0.0.3"}, Zone: &zoneB},
+		},
+	}
+
+	got := subsetEndpointSlices([]*discoveryv1.EndpointSlice{slice}, "rev-1", 2, sets.NewString(zoneA))
+
+	if len(got) != 2 {
+		t.Fatalf("got %d endpoints, want 2 (one same-zone plus one fallback)", len(got))
+	}
+	if !endpointAddrs(got).Has("10.0.0.1") {
+		t.Error("the lone same-zone candidate should always be selected before falling back")
+	}
+}
+
+func TestEndpointAddrs(t *testing.T) {
+	eps := []resources.SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}},
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.2", "fd00::2"}}},
+	}
+	addrs := endpointAddrs(eps)
+	want := sets.NewString("10.0.0.1", "10.0.0.2", "fd00::2")
+	if !addrs.Equal(want) {
+		t.Errorf("endpointAddrs() = %v, want %v", addrs.List(), want.List())
+	}
+}
+
+func TestSubsetEndpointSlicesManyEndpoints(t *testing.T) {
+	n := 50
+	addrs := make([]string, n)
+	for i := range addrs {
+		addrs[i] = fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+	}
+	slice := sliceWithAddrs(discoveryv1.AddressTypeIPv4, addrs, nil)
+
+	got := subsetEndpointSlices([]*discoveryv1.EndpointSlice{slice}, "rev-1", 5, nil)
+	if len(got) != 5 {
+		t.Fatalf("got %d endpoints, want 5", len(got))
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/metrics"
+
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+var (
+	modeOverrideFromKey   = tag.MustNewKey("from")
+	modeOverrideToKey     = tag.MustNewKey("to")
+	modeOverrideReasonKey = tag.MustNewKey("reason")
+	sksKeyTag             = tag.MustNewKey("sks")
+	subReconcilerKeyTag   = tag.MustNewKey("sub_reconciler")
+	sksModeKeyTag         = tag.MustNewKey("mode")
+
+	modeOverrideCountM = stats.Int64(
+		"sks_mode_override_total",
+		"Number of times the reconciler overrode the SKS's requested mode",
+		stats.UnitDimensionless)
+	subsetChurnCountM = stats.Int64(
+		"sks_subset_churn_total",
+		"Number of reconciles where the selected activator subset differed from the previous one",
+		stats.UnitDimensionless)
+	reconcileDurationM = stats.Float64(
+		"sks_reconcile_duration_seconds",
+		"Time spent in each SKS sub-reconciler",
+		stats.UnitSeconds)
+	publicEndpointCountM = stats.Int64(
+		"sks_public_endpoint_count",
+		"Number of addresses currently programmed into the public Endpoints/EndpointSlices",
+		stats.UnitDimensionless)
+	activatorSubsetSizeM = stats.Int64(
+		"sks_activator_subset_size",
+		"Number of activator addresses currently selected into the public Endpoints",
+		stats.UnitDimensionless)
+	boundedLoadRelaxedCountM = stats.Int64(
+		"sks_bounded_load_relaxed_total",
+		"Number of times the bounded-load subset selection had to relax its load bound to produce enough addresses",
+		stats.UnitDimensionless)
+)
+
+func init() {
+	if err := view.Register(
+		&view.View{
+			Description: modeOverrideCountM.Description(),
+			Measure:     modeOverrideCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{modeOverrideFromKey, modeOverrideToKey, modeOverrideReasonKey},
+		},
+		&view.View{
+			Description: subsetChurnCountM.Description(),
+			Measure:     subsetChurnCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{sksKeyTag},
+		},
+		&view.View{
+			Description: reconcileDurationM.Description(),
+			Measure:     reconcileDurationM,
+			Aggregation: view.Distribution(0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10),
+			TagKeys:     []tag.Key{subReconcilerKeyTag},
+		},
+		&view.View{
+			Description: publicEndpointCountM.Description(),
+			Measure:     publicEndpointCountM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{sksModeKeyTag},
+		},
+		&view.View{
+			Description: activatorSubsetSizeM.Description(),
+			Measure:     activatorSubsetSizeM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: boundedLoadRelaxedCountM.Description(),
+			Measure:     boundedLoadRelaxedCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{sksKeyTag},
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
+// recordModeOverride records a mode-flip and fires a Normal Event on sks, so
+// an operator watching a flapping revision doesn't have to go digging
+// through reconciler logs to see it.
+func recordModeOverride(ctx context.Context, sks *netv1alpha1.ServerlessService, from, to netv1alpha1.SKSOperationMode, reason string) {
+	mctx, err := tag.New(ctx,
+		tag.Insert(modeOverrideFromKey, string(from)),
+		tag.Insert(modeOverrideToKey, string(to)),
+		tag.Insert(modeOverrideReasonKey, reason))
+	if err == nil {
+		metrics.Record(mctx, modeOverrideCountM.M(1))
+	}
+	controller.GetEventRecorder(ctx).Eventf(sks, corev1.EventTypeNormal, "ModeOverride",
+		"Forcing SKS mode from %s to %s: %s", from, to, reason)
+}
+
+// recordOwnershipConflict fires a Warning Event on sks when the reconciler
+// finds a resource it doesn't own but needs to manage.
+func recordOwnershipConflict(ctx context.Context, sks *netv1alpha1.ServerlessService, kind, name string) {
+	controller.GetEventRecorder(ctx).Eventf(sks, corev1.EventTypeWarning, "OwnershipConflict",
+		"SKS does not own %s %q", kind, name)
+}
+
+// recordReconcileDuration records the time a sub-reconciler took, tagged by
+// its name (one of "private-service", "public-service", "public-endpoints").
+func recordReconcileDuration(ctx context.Context, sub string, d time.Duration) {
+	mctx, err := tag.New(ctx, tag.Insert(subReconcilerKeyTag, sub))
+	if err != nil {
+		return
+	}
+	metrics.Record(mctx, reconcileDurationM.M(d.Seconds()))
+}
+
+// recordPublicEndpointCount records the gauge of addresses currently
+// programmed into the public Endpoints/EndpointSlices, tagged by the mode
+// that produced them.
+func recordPublicEndpointCount(ctx context.Context, mode netv1alpha1.SKSOperationMode, n int) {
+	mctx, err := tag.New(ctx, tag.Insert(sksModeKeyTag, string(mode)))
+	if err != nil {
+		return
+	}
+	metrics.Record(mctx, publicEndpointCountM.M(int64(n)))
+}
+
+// recordActivatorSubsetSize records the gauge of activator addresses
+// currently selected into the public Endpoints.
+func recordActivatorSubsetSize(ctx context.Context, n int) {
+	metrics.Record(ctx, activatorSubsetSizeM.M(int64(n)))
+}
+
+// recordSubsetChurnIfChanged compares the newly selected activator subset
+// for sksKey against the cached previous selection (maintained on r),
+// recording sks_subset_churn_total and firing an Event when they differ.
+func (r *reconciler) recordSubsetChurnIfChanged(ctx context.Context, sks *netv1alpha1.ServerlessService, sksKey string, addrs sets.String) {
+	r.churnMu.Lock()
+	if r.lastSubset == nil {
+		r.lastSubset = map[string]sets.String{}
+	}
+	prev, ok := r.lastSubset[sksKey]
+	r.lastSubset[sksKey] = addrs
+	r.churnMu.Unlock()
+
+	if ok && prev.Equal(addrs) {
+		return
+	}
+	mctx, err := tag.New(ctx, tag.Insert(sksKeyTag, sksKey))
+	if err == nil {
+		metrics.Record(mctx, subsetChurnCountM.M(1))
+	}
+	controller.GetEventRecorder(ctx).Eventf(sks, corev1.EventTypeNormal, "SubsetChurn",
+		"Activator subset changed (%d addresses)", addrs.Len())
+}
+
+// recordBoundedLoadRelaxed records that the bounded-load subset selection for
+// sksKey had to relax its load bound to produce enough addresses, meaning the
+// activator ring is genuinely overloaded relative to the requested subset
+// size rather than this being routine rebalancing.
+func recordBoundedLoadRelaxed(ctx context.Context, sksKey string) {
+	mctx, err := tag.New(ctx, tag.Insert(sksKeyTag, sksKey))
+	if err != nil {
+		return
+	}
+	metrics.Record(mctx, boundedLoadRelaxedCountM.M(1))
+}
+
+// clearSubsetChurnCache drops sksKey's cached subset, e.g. when its SKS is
+// being deleted.
+func (r *reconciler) clearSubsetChurnCache(sksKey string) {
+	r.churnMu.Lock()
+	delete(r.lastSubset, sksKey)
+	r.churnMu.Unlock()
+}
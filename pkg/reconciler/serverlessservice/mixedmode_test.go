@@ -0,0 +1,155 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/serving/pkg/reconciler/serverlessservice/resources"
+)
+
+func TestProxyFraction(t *testing.T) {
+	cases := []struct {
+		name      string
+		annos     map[string]string
+		wantFrac  float64
+		wantMixed bool
+	}{
+		{"absent", nil, 0, false},
+		{"not a float", map[string]string{proxyFractionAnnotationKey: "lots"}, 0, false},
+		{"zero is out of range", map[string]string{proxyFractionAnnotationKey: "0"}, 0, false},
+		{"negative is out of range", map[string]string{proxyFractionAnnotationKey: "-0.5"}, 0, false},
+		{"above one is out of range", map[string]string{proxyFractionAnnotationKey: "1.5"}, 0, false},
+		{"valid fraction", map[string]string{proxyFractionAnnotationKey: "0.25"}, 0.25, true},
+		{"one is the top of the valid range", map[string]string{proxyFractionAnnotationKey: "1"}, 1, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sks := &netv1alpha1.ServerlessService{ObjectMeta: metav1.ObjectMeta{Annotations: c.annos}}
+			gotFrac, gotMixed := proxyFraction(sks)
+			if gotMixed != c.wantMixed {
+				t.Errorf("useMixed = %t, want %t", gotMixed, c.wantMixed)
+			}
+			if gotFrac != c.wantFrac {
+				t.Errorf("fraction = %v, want %v", gotFrac, c.wantFrac)
+			}
+		})
+	}
+}
+
+func endpointsWith(addrs ...string) *corev1.Endpoints {
+	eps := &corev1.Endpoints{}
+	var addresses []corev1.EndpointAddress
+	for _, a := range addrs {
+		addresses = append(addresses, corev1.EndpointAddress{IP: a})
+	}
+	if len(addresses) > 0 {
+		eps.Subsets = []corev1.EndpointSubset{{Addresses: addresses}}
+	}
+	return eps
+}
+
+func TestMergeEndpointsDedupsByIP(t *testing.T) {
+	base := endpointsWith("10.0.0.1")
+	additional := endpointsWith("10.0.0.1", "10.0.0.2")
+
+	merged := mergeEndpoints(base, additional)
+
+	if got := subsetIPs(merged); !got.Equal(sets.NewString("10.0.0.1", "10.0.0.2")) {
+		t.Errorf("merged IPs = %v, want [10.0.0.1 10.0.0.2]", got.List())
+	}
+}
+
+func TestMergeEndpointsNoNewAddresses(t *testing.T) {
+	base := endpointsWith("10.0.0.1")
+	additional := endpointsWith("10.0.0.1")
+
+	merged := mergeEndpoints(base, additional)
+
+	if got := subsetIPs(merged); !got.Equal(sets.NewString("10.0.0.1")) {
+		t.Errorf("merged IPs = %v, want [10.0.0.1] unchanged", got.List())
+	}
+}
+
+func TestMergeEndpointsEmptyBase(t *testing.T) {
+	base := endpointsWith()
+	additional := endpointsWith("10.0.0.1")
+
+	merged := mergeEndpoints(base, additional)
+
+	if got := subsetIPs(merged); !got.Equal(sets.NewString("10.0.0.1")) {
+		t.Errorf("merged IPs = %v, want [10.0.0.1]", got.List())
+	}
+}
+
+func TestMergeSliceEndpointsDedupsByAddress(t *testing.T) {
+	base := []resources.SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}},
+	}
+	additional := []resources.SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}}, // duplicate, should be dropped
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}}}, // new, should be kept
+	}
+
+	merged := mergeSliceEndpoints(base, additional)
+
+	if len(merged) != 2 {
+		t.Fatalf("got %d endpoints, want 2 (duplicate address deduped)", len(merged))
+	}
+	if !endpointAddrs(merged).Equal(sets.NewString("10.0.0.1", "10.0.0.2")) {
+		t.Errorf("merged addresses = %v, want [10.0.0.1 10.0.0.2]", endpointAddrs(merged).List())
+	}
+}
+
+func TestMergeSliceEndpointsPreservesBaseOrder(t *testing.T) {
+	base := []resources.SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}},
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}}},
+	}
+	merged := mergeSliceEndpoints(base, nil)
+	for i, ep := range base {
+		if merged[i].Addresses[0] != ep.Addresses[0] {
+			t.Errorf("merged[%d] = %v, want base unchanged at that position", i, merged[i].Addresses)
+		}
+	}
+}
+
+// TestUseMixedRespectsForcedOverride exercises the same guard
+// reconcilePublicEndpoints and reconcilePublicEndpointSlices apply: mixed
+// mode must not kick in while the Serve/Proxy mode is being forced away from
+// what the SKS actually requested (e.g. no ready private pods yet), since the
+// override already picked the one safe source of truth.
+func TestUseMixedRespectsForcedOverride(t *testing.T) {
+	sks := &netv1alpha1.ServerlessService{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{proxyFractionAnnotationKey: "0.5"}},
+		Spec:       netv1alpha1.ServerlessServiceSpec{Mode: netv1alpha1.SKSOperationModeServe},
+	}
+
+	_, useMixed := proxyFraction(sks)
+	forcedMode := netv1alpha1.SKSOperationModeProxy // e.g. overridden due to no ready private pods
+	useMixed = useMixed && forcedMode == sks.Spec.Mode
+
+	if useMixed {
+		t.Error("useMixed must be false once the mode has been forced away from sks.Spec.Mode")
+	}
+}
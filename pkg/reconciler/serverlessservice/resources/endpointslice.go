@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+// maxEndpointsPerSlice mirrors the upstream EndpointSlice controller's own
+// default (--max-endpoints-per-slice), so a revision with very large
+// activator/pod fan-out still produces objects the API server will accept.
+const maxEndpointsPerSlice = 1000
+
+// SliceEndpoint carries a discoveryv1.Endpoint together with the AddressType
+// and Ports of the EndpointSlice it was read from. EndpointSlice encodes
+// address family and port set at the slice level rather than per endpoint,
+// so callers that flatten endpoints out of multiple slices (e.g. to subset
+// or merge them) need to carry that context along to rebuild valid slices.
+type SliceEndpoint struct {
+	discoveryv1.Endpoint
+	AddressType discoveryv1.AddressType
+	Ports       []discoveryv1.EndpointPort
+}
+
+// MakePublicEndpointSlices builds the discoveryv1.EndpointSlices that back
+// sks's public Endpoints, owned by sks. It groups endpoints by AddressType
+// so a dual-stack revision gets one IPv4 slice and one IPv6 slice instead of
+// an invalid mixed-family slice, and chunks each group to
+// maxEndpointsPerSlice, matching how the built-in EndpointSlice controller
+// shards large Services.
+func MakePublicEndpointSlices(sks *netv1alpha1.ServerlessService, endpoints []SliceEndpoint) []*discoveryv1.EndpointSlice {
+	var order []discoveryv1.AddressType
+	byType := map[discoveryv1.AddressType][]SliceEndpoint{}
+	for _, ep := range endpoints {
+		if _, ok := byType[ep.AddressType]; !ok {
+			order = append(order, ep.AddressType)
+		}
+		byType[ep.AddressType] = append(byType[ep.AddressType], ep)
+	}
+
+	var slices []*discoveryv1.EndpointSlice
+	for _, at := range order {
+		eps := byType[at]
+		for i, chunkIdx := 0, 0; i < len(eps); i, chunkIdx = i+maxEndpointsPerSlice, chunkIdx+1 {
+			end := i + maxEndpointsPerSlice
+			if end > len(eps) {
+				end = len(eps)
+			}
+			slices = append(slices, makeEndpointSlice(sks, at, eps[i:end], chunkIdx))
+		}
+	}
+	return slices
+}
+
+func makeEndpointSlice(sks *netv1alpha1.ServerlessService, at discoveryv1.AddressType, chunk []SliceEndpoint, chunkIdx int) *discoveryv1.EndpointSlice {
+	name := fmt.Sprintf("%s-%s", sks.Name, addressTypeSuffix(at))
+	if chunkIdx > 0 {
+		name = fmt.Sprintf("%s-%d", name, chunkIdx)
+	}
+
+	endpoints := make([]discoveryv1.Endpoint, 0, len(chunk))
+	var ports []discoveryv1.EndpointPort
+	for _, ep := range chunk {
+		endpoints = append(endpoints, ep.Endpoint)
+		if ports == nil {
+			ports = ep.Ports
+		}
+	}
+
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sks.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: sks.Name,
+				discoveryv1.LabelManagedBy:   "serverlessservice-controller",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(sks, netv1alpha1.SchemeGroupVersion.WithKind("ServerlessService")),
+			},
+		},
+		AddressType: at,
+		Endpoints:   endpoints,
+		Ports:       ports,
+	}
+}
+
+// addressTypeSuffix gives each AddressType's slices a stable, human readable
+// name suffix so e.g. a dual-stack SKS's IPv4 and IPv6 slices don't collide.
+func addressTypeSuffix(at discoveryv1.AddressType) string {
+	switch at {
+	case discoveryv1.AddressTypeIPv6:
+		return "ipv6"
+	case discoveryv1.AddressTypeFQDN:
+		return "fqdn"
+	default:
+		return "ipv4"
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+func sks() *netv1alpha1.ServerlessService {
+	return &netv1alpha1.ServerlessService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "rev-1"},
+	}
+}
+
+func TestMakePublicEndpointSlicesGroupsByAddressType(t *testing.T) {
+	eps := []SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}, AddressType: discoveryv1.AddressTypeIPv4},
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"fd00::1"}}, AddressType: discoveryv1.AddressTypeIPv6},
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}}, AddressType: discoveryv1.AddressTypeIPv4},
+	}
+
+	slices := MakePublicEndpointSlices(sks(), eps)
+
+	if got := len(slices); got != 2 {
+		t.Fatalf("got %d slices, want 2 (one per AddressType)", got)
+	}
+	byType := map[discoveryv1.AddressType]*discoveryv1.EndpointSlice{}
+	for _, s := range slices {
+		byType[s.AddressType] = s
+	}
+	if got := len(byType[discoveryv1.AddressTypeIPv4].Endpoints); got != 2 {
+		t.Errorf("IPv4 slice has %d endpoints, want 2", got)
+	}
+	if got := len(byType[discoveryv1.AddressTypeIPv6].Endpoints); got != 1 {
+		t.Errorf("IPv6 slice has %d endpoints, want 1", got)
+	}
+	if byType[discoveryv1.AddressTypeIPv4].Name == byType[discoveryv1.AddressTypeIPv6].Name {
+		t.Error("IPv4 and IPv6 slices must not share a name")
+	}
+}
+
+func TestMakePublicEndpointSlicesChunksLargeGroups(t *testing.T) {
+	n := maxEndpointsPerSlice + 1
+	eps := make([]SliceEndpoint, n)
+	for i := range eps {
+		eps[i] = SliceEndpoint{
+			Endpoint:    discoveryv1.Endpoint{Addresses: []string{fmt.Sprintf("10.0.%d.%d", i/256, i%256)}},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		}
+	}
+
+	slices := MakePublicEndpointSlices(sks(), eps)
+
+	if got := len(slices); got != 2 {
+		t.Fatalf("got %d slices, want 2 for %d endpoints with a %d cap", got, n, maxEndpointsPerSlice)
+	}
+	if got := len(slices[0].Endpoints); got != maxEndpointsPerSlice {
+		t.Errorf("first slice has %d endpoints, want %d", got, maxEndpointsPerSlice)
+	}
+	if got := len(slices[1].Endpoints); got != 1 {
+		t.Errorf("second slice has %d endpoints, want 1", got)
+	}
+	if slices[0].Name == slices[1].Name {
+		t.Error("chunked slices must not share a name")
+	}
+}
+
+func TestMakePublicEndpointSlicesOwnerReference(t *testing.T) {
+	s := sks()
+	slices := MakePublicEndpointSlices(s, []SliceEndpoint{
+		{Endpoint: discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}}, AddressType: discoveryv1.AddressTypeIPv4},
+	})
+	if got := len(slices); got != 1 {
+		t.Fatalf("got %d slices, want 1", got)
+	}
+	if !metav1.IsControlledBy(slices[0], s) {
+		t.Error("EndpointSlice is not controlled by its owning SKS")
+	}
+	if got := slices[0].Labels[discoveryv1.LabelServiceName]; got != s.Name {
+		t.Errorf("LabelServiceName = %q, want %q", got, s.Name)
+	}
+}
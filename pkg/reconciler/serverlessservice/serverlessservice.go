@@ -19,7 +19,9 @@ package serverlessservice
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -27,13 +29,16 @@ import (
 	"go.uber.org/zap"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoveryv1listers "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/tools/cache"
 	sksreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/serverlessservice"
 
@@ -48,6 +53,97 @@ import (
 	presources "knative.dev/serving/pkg/resources"
 )
 
+// proxyFractionAnnotationKey gates the mixed-mode public-endpoints path added
+// below. It is read as a float in [0, 1]: the fraction of NumActivators
+// activator IPs to keep alongside the ready private pods while a revision
+// warms up or drains, instead of cutting over in one step between Serve and
+// Proxy mode. This lives behind an annotation rather than a new
+// Spec.ProxyFraction field so the autoscaler can ramp it without an API
+// change; it is expected to graduate to a first-class field once the
+// approach is proven out.
+const proxyFractionAnnotationKey = "networking.internal.knative.dev/proxy-fraction"
+
+// proxyFraction returns the mixed-mode proxy fraction for sks and whether
+// mixed mode should be used at all. Mixed mode only applies when the
+// annotation is present, parses to a value in (0, 1], and both the private
+// and activator endpoints have at least one ready address -- outside that
+// range the existing Serve/Proxy behavior already does the right thing.
+func proxyFraction(sks *netv1alpha1.ServerlessService) (float64, bool) {
+	raw, ok := sks.Annotations[proxyFractionAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f <= 0 || f > 1 {
+		return 0, false
+	}
+	return f, true
+}
+
+// mergeEndpoints returns a new Endpoints combining the ready addresses of
+// base with the additional addresses, de-duplicating by IP and preferring
+// base's subset/port shape. It is used to program the public Endpoints with
+// a weighted union of private pods and a subset of activators during a
+// gradual cutover (mixed mode, see proxyFractionAnnotationKey).
+func mergeEndpoints(base, additional *corev1.Endpoints) *corev1.Endpoints {
+	seen := sets.NewString()
+	for _, ss := range base.Subsets {
+		for _, addr := range ss.Addresses {
+			seen.Insert(addr.IP)
+		}
+	}
+
+	merged := base.DeepCopy()
+	for _, ss := range additional.Subsets {
+		var extra []corev1.EndpointAddress
+		for _, addr := range ss.Addresses {
+			if !seen.Has(addr.IP) {
+				seen.Insert(addr.IP)
+				extra = append(extra, addr)
+			}
+		}
+		if len(extra) == 0 {
+			continue
+		}
+		if len(merged.Subsets) == 0 {
+			merged.Subsets = append(merged.Subsets, corev1.EndpointSubset{Addresses: extra, Ports: ss.Ports})
+			continue
+		}
+		// Fold the extra addresses into the first subset; FilterSubsetPorts
+		// normalizes ports downstream, so subset granularity here doesn't
+		// matter for what finally gets programmed.
+		merged.Subsets[0].Addresses = append(merged.Subsets[0].Addresses, extra...)
+	}
+	return merged
+}
+
+// mergeSliceEndpoints returns base with additional's addresses appended,
+// de-duplicating by address. Used to program the public EndpointSlices with
+// a union of private pods and a subset of activators during a gradual
+// cutover (mixed mode).
+func mergeSliceEndpoints(base, additional []resources.SliceEndpoint) []resources.SliceEndpoint {
+	seen := sets.NewString()
+	for _, ep := range base {
+		seen.Insert(ep.Addresses...)
+	}
+
+	merged := make([]resources.SliceEndpoint, len(base), len(base)+len(additional))
+	copy(merged, base)
+	for _, ep := range additional {
+		isNew := false
+		for _, addr := range ep.Addresses {
+			if !seen.Has(addr) {
+				isNew = true
+				seen.Insert(addr)
+			}
+		}
+		if isNew {
+			merged = append(merged, ep)
+		}
+	}
+	return merged
+}
+
 // reconciler implements controller.Reconciler for Service resources.
 type reconciler struct {
 	kubeclient kubernetes.Interface
@@ -56,6 +152,53 @@ type reconciler struct {
 	serviceLister   corev1listers.ServiceLister
 	endpointsLister corev1listers.EndpointsLister
 
+	// endpointSliceLister indexes discoveryv1.EndpointSlice objects for the
+	// activator and private services. It is only populated (non-nil) when the
+	// EndpointSlice controller feature is enabled; reconcilePublicEndpoints
+	// decides which public-backing path to run from enableEndpointSlices.
+	endpointSliceLister discoveryv1listers.EndpointSliceLister
+
+	// enableEndpointSlices gates the EndpointSlice-backed reconciliation path.
+	// It is set once at controller construction time from config-features and
+	// is read-only thereafter.
+	enableEndpointSlices bool
+
+	// enableBoundedLoadHashing gates consistent hashing with bounded loads for
+	// the activator subset selection in subsetEndpoints; when false,
+	// subsetEndpoints keeps using the legacy hash.ChooseSubset, which makes
+	// independent per-revision choices and can produce hot activators under
+	// churn. Set once at controller construction time from controller config.
+	enableBoundedLoadHashing bool
+
+	// boundedLoadOverflow is the `c` factor in consistent hashing with
+	// bounded loads: an activator may carry up to ceil(avgLoad*c) revisions
+	// before the ring walk skips it in favor of its successor. Configured via
+	// controller config; defaults to defaultBoundedLoadOverflow.
+	boundedLoadOverflow float64
+
+	// mu guards activatorLoads and revisionActivators below, which are
+	// mutated by concurrent ReconcileKind calls.
+	mu sync.Mutex
+
+	// activatorLoads tracks, for each known activator IP, the number of
+	// revisions currently assigned to it by the bounded-load subset
+	// selection. Only maintained when enableBoundedLoadHashing is set.
+	activatorLoads map[string]int
+
+	// revisionActivators remembers the activator IPs last assigned to each
+	// SKS (keyed by namespace/name), so that on the next reconcile only the
+	// delta needs to be applied to activatorLoads, and so a deleted SKS's
+	// load can be released.
+	revisionActivators map[string]sets.String
+
+	// churnMu guards lastSubset, the subset-churn cache below.
+	churnMu sync.Mutex
+
+	// lastSubset caches, per SKS (keyed by namespace/name), the activator
+	// subset selected on the previous reconcile, so sks_subset_churn_total
+	// only increments when the selection actually changes.
+	lastSubset map[string]sets.String
+
 	// Used to get PodScalables from object references.
 	listerFactory func(schema.GroupVersionResource) (cache.GenericLister, error)
 }
@@ -73,15 +216,24 @@ func (r *reconciler) ReconcileKind(ctx context.Context, sks *netv1alpha1.Serverl
 	logger := logging.FromContext(ctx)
 	// Don't reconcile if we're being deleted.
 	if sks.GetDeletionTimestamp() != nil {
+		sksKey := sks.Namespace + "/" + sks.Name
+		r.releaseActivatorLoad(sksKey)
+		r.clearSubsetChurnCache(sksKey)
 		return nil
 	}
 
-	for i, fn := range []func(context.Context, *netv1alpha1.ServerlessService) error{
-		r.reconcilePrivateService, // First make sure our data source is setup.
-		r.reconcilePublicService,
-		r.reconcilePublicEndpoints,
+	for i, step := range []struct {
+		name string
+		fn   func(context.Context, *netv1alpha1.ServerlessService) error
+	}{
+		{"private-service", r.reconcilePrivateService}, // First make sure our data source is setup.
+		{"public-service", r.reconcilePublicService},
+		{"public-endpoints", r.reconcilePublicEndpoints},
 	} {
-		if err := fn(ctx, sks); err != nil {
+		start := time.Now()
+		err := step.fn(ctx, sks)
+		recordReconcileDuration(ctx, step.name, time.Since(start))
+		if err != nil {
 			logger.Debugw(strconv.Itoa(i)+": reconcile failed", zap.Error(err))
 			return err
 		}
@@ -107,6 +259,7 @@ func (r *reconciler) reconcilePublicService(ctx context.Context, sks *netv1alpha
 	} else if err != nil {
 		return fmt.Errorf("failed to get public K8s Service: %w", err)
 	} else if !metav1.IsControlledBy(srv, sks) {
+		recordOwnershipConflict(ctx, sks, "Service", sn)
 		sks.Status.MarkEndpointsNotOwned("Service", sn)
 		return fmt.Errorf("SKS: %s does not own Service: %s", sks.Name, sn)
 	} else {
@@ -127,6 +280,19 @@ func (r *reconciler) reconcilePublicService(ctx context.Context, sks *netv1alpha
 	return nil
 }
 
+// subsetIPs collects the addresses currently present in eps, used as the key
+// for subset-churn detection: two selections with the same IPs (regardless
+// of which Subset/port they landed in) are not churn.
+func subsetIPs(eps *corev1.Endpoints) sets.String {
+	ips := sets.NewString()
+	for _, ss := range eps.Subsets {
+		for _, addr := range ss.Addresses {
+			ips.Insert(addr.IP)
+		}
+	}
+	return ips
+}
+
 // subsetEndpoints computes a subset of all endpoints of size `n` using a consistent
 // selection algorithm. For non empty input, subsetEndpoints returns a copy of the
 // input with the irrelevant endpoints and empty subsets filtered out, if the input
@@ -187,7 +353,118 @@ func subsetEndpoints(eps *corev1.Endpoints, target string, n int) *corev1.Endpoi
 	return neps
 }
 
+// subsetEndpoints picks the activator subset for sksKey/target, either via
+// the legacy independent-per-revision hash.ChooseSubset, or, when
+// enableBoundedLoadHashing is set, via consistent hashing with bounded loads
+// so that activator load stays balanced across revisions under activator
+// churn.
+func (r *reconciler) subsetEndpoints(ctx context.Context, eps *corev1.Endpoints, sksKey, target string, n int) *corev1.Endpoints {
+	if !r.enableBoundedLoadHashing {
+		return subsetEndpoints(eps, target, n)
+	}
+	return r.subsetEndpointsBoundedLoad(ctx, eps, sksKey, target, n)
+}
+
+// subsetEndpointsBoundedLoad behaves like subsetEndpoints, but the selection
+// comes from chooseBoundedLoadSubset against the reconciler's shared
+// activatorLoads table instead of an independent hash per revision.
+func (r *reconciler) subsetEndpointsBoundedLoad(ctx context.Context, eps *corev1.Endpoints, sksKey, target string, n int) *corev1.Endpoints {
+	if len(eps.Subsets) == 0 || n == 0 {
+		return eps
+	}
+
+	addrs := make(sets.String, len(eps.Subsets[0].Addresses))
+	for _, ss := range eps.Subsets {
+		for _, addr := range ss.Addresses {
+			addrs.Insert(addr.IP)
+		}
+	}
+	if len(addrs) <= n {
+		return eps
+	}
+
+	selection := r.chooseBoundedLoadSubsetFor(ctx, sksKey, target, n, addrs)
+
+	neps := eps.DeepCopy()
+	ri, w := 0, 0
+	for ri < len(neps.Subsets) {
+		ss := neps.Subsets[ri]
+		ra, wa := 0, 0
+		for ra < len(ss.Addresses) {
+			if selection.Has(ss.Addresses[ra].IP) {
+				ss.Addresses[wa] = ss.Addresses[ra]
+				wa++
+			}
+			ra++
+		}
+		if wa > 0 {
+			ss.Addresses = ss.Addresses[:wa]
+			neps.Subsets[w] = ss
+			w++
+		}
+		ri++
+	}
+	neps.Subsets = neps.Subsets[:w]
+	return neps
+}
+
+// chooseBoundedLoadSubsetFor releases sksKey's previous assignment (if any),
+// computes the current average per-activator load across all known
+// revisions, and returns the new bounded-load selection, recording it as
+// sksKey's current assignment. If the bound had to be relaxed to produce `n`
+// addresses (the ring is overloaded relative to `n`), it records
+// sks_bounded_load_relaxed_total so that is visible rather than silent.
+func (r *reconciler) chooseBoundedLoadSubsetFor(ctx context.Context, sksKey, target string, n int, addrs sets.String) sets.String {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activatorLoads == nil {
+		r.activatorLoads = map[string]int{}
+	}
+	if r.revisionActivators == nil {
+		r.revisionActivators = map[string]sets.String{}
+	}
+	if prev, ok := r.revisionActivators[sksKey]; ok {
+		for ip := range prev {
+			r.activatorLoads[ip]--
+		}
+	}
+
+	overflow := r.boundedLoadOverflow
+	if overflow <= 0 {
+		overflow = defaultBoundedLoadOverflow
+	}
+	avgLoad := float64(len(r.revisionActivators)) * float64(n) / float64(addrs.Len())
+
+	selection, relaxed := chooseBoundedLoadSubset(activatorRing(addrs), target, n, r.activatorLoads, avgLoad, overflow)
+	if relaxed {
+		recordBoundedLoadRelaxed(ctx, sksKey)
+	}
+	r.revisionActivators[sksKey] = selection
+	return selection
+}
+
+// releaseActivatorLoad removes sksKey's contribution to activatorLoads,
+// e.g. when the owning SKS is being deleted.
+func (r *reconciler) releaseActivatorLoad(sksKey string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.revisionActivators[sksKey]
+	if !ok {
+		return
+	}
+	for ip := range prev {
+		r.activatorLoads[ip]--
+	}
+	delete(r.revisionActivators, sksKey)
+}
+
 func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alpha1.ServerlessService) error {
+	if r.enableEndpointSlices {
+		return r.reconcilePublicEndpointSlices(ctx, sks)
+	}
+
 	logger := logging.FromContext(ctx)
 	dlogger := logger.Desugar()
 
@@ -243,20 +520,51 @@ func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alp
 	// since those endpoints are the ones programmed into the VirtualService.
 	mode := sks.Spec.Mode
 	if pvtReady == 0 {
-		logger.Info("Forcing SKS into Proxy mode, insufficient ready endpoints.")
+		if mode != netv1alpha1.SKSOperationModeProxy {
+			logger.Info("Forcing SKS into Proxy mode, insufficient ready endpoints.")
+			recordModeOverride(ctx, sks, mode, netv1alpha1.SKSOperationModeProxy, "InsufficientReadyEndpoints")
+		}
 		mode = netv1alpha1.SKSOperationModeProxy
 	} else {
 		foundServingEndpoints = true
 	}
 	if sharedReady == 0 {
-		logger.Info("Forcing SKS into Serve mode, no activator endpoints.")
+		if mode != netv1alpha1.SKSOperationModeServe {
+			logger.Info("Forcing SKS into Serve mode, no activator endpoints.")
+			recordModeOverride(ctx, sks, mode, netv1alpha1.SKSOperationModeServe, "NoActivatorEndpoints")
+		}
 		mode = netv1alpha1.SKSOperationModeServe
 	}
-	switch mode {
-	case netv1alpha1.SKSOperationModeServe:
+	// Mixed mode: keep a fraction of the activator subset alongside the ready
+	// private pods, for a bounded window while a revision warms up or
+	// drains, instead of cutting over between Serve and Proxy in one step.
+	// It only kicks in when both sources are actually usable; the Proxy/
+	// Serve overrides above already take precedence otherwise.
+	mixedFraction, useMixed := proxyFraction(sks)
+	useMixed = useMixed && mode == sks.Spec.Mode // don't mix during a forced override
+	useMixed = useMixed && pvtReady > 0 && sharedReady > 0
+
+	sksKey := sks.Namespace + "/" + sks.Name
+	activatorSubsetSize := 0
+	switch {
+	case useMixed:
+		numMixed := int(math.Ceil(mixedFraction * float64(sks.Spec.NumActivators)))
+		activatorSubset := r.subsetEndpoints(ctx, activatorEps, sksKey, sks.Name, numMixed)
+		activatorSubsetSize = presources.ReadyAddressCount(activatorSubset)
+		r.recordSubsetChurnIfChanged(ctx, sks, sksKey, subsetIPs(activatorSubset))
+		srcEps = mergeEndpoints(pvtEps, activatorSubset)
+	case mode == netv1alpha1.SKSOperationModeServe:
 		srcEps = pvtEps
-	case netv1alpha1.SKSOperationModeProxy:
-		srcEps = subsetEndpoints(activatorEps, sks.Name, int(sks.Spec.NumActivators))
+		r.clearSubsetChurnCache(sksKey)
+	case mode == netv1alpha1.SKSOperationModeProxy:
+		activatorSubset := r.subsetEndpoints(ctx, activatorEps, sksKey, sks.Name, int(sks.Spec.NumActivators))
+		activatorSubsetSize = presources.ReadyAddressCount(activatorSubset)
+		r.recordSubsetChurnIfChanged(ctx, sks, sksKey, subsetIPs(activatorSubset))
+		srcEps = activatorSubset
+	}
+	recordActivatorSubsetSize(ctx, activatorSubsetSize)
+	if srcEps != nil {
+		recordPublicEndpointCount(ctx, mode, presources.ReadyAddressCount(srcEps))
 	}
 
 	sn := sks.Name
@@ -272,6 +580,7 @@ func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alp
 	} else if err != nil {
 		return fmt.Errorf("failed to get public K8s Endpoints: %w", err)
 	} else if !metav1.IsControlledBy(eps, sks) {
+		recordOwnershipConflict(ctx, sks, "Endpoints", sn)
 		sks.Status.MarkEndpointsNotOwned("Endpoints", sn)
 		return fmt.Errorf("SKS: %s does not own Endpoints: %s", sks.Name, sn)
 	} else {
@@ -291,9 +600,10 @@ func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alp
 		dlogger.Info("No ready endpoints backing revision")
 		sks.Status.MarkEndpointsNotReady("NoHealthyBackends")
 	}
-	// If we have no backends or if we're in the proxy mode, then
-	// activator backs this revision.
-	if !foundServingEndpoints || sks.Spec.Mode == netv1alpha1.SKSOperationModeProxy {
+	// If we have no backends, if we're in proxy mode, or if mixed mode is
+	// still keeping a non-zero activator fraction in the mix, then the
+	// activator partially or fully backs this revision.
+	if !foundServingEndpoints || sks.Spec.Mode == netv1alpha1.SKSOperationModeProxy || useMixed {
 		sks.Status.MarkActivatorEndpointsPopulated()
 	} else {
 		sks.Status.MarkActivatorEndpointsRemoved()
@@ -303,6 +613,319 @@ func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alp
 	return nil
 }
 
+// endpointSliceReady reports whether an endpoint from an EndpointSlice should
+// be treated as ready backend, i.e. it is not in the process of terminating.
+// Conditions are pointers and default to true when unset, per the
+// EndpointSlice API contract.
+func endpointSliceReady(cond discoveryv1.EndpointConditions) bool {
+	if cond.Terminating != nil && *cond.Terminating {
+		return false
+	}
+	return cond.Ready == nil || *cond.Ready
+}
+
+// listEndpointSlices returns all EndpointSlices backing the given Service,
+// selected the same way kube-proxy and other EndpointSlice consumers do: by
+// the well-known "kubernetes.io/service-name" label.
+func (r *reconciler) listEndpointSlices(namespace, serviceName string) ([]*discoveryv1.EndpointSlice, error) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+	return r.endpointSliceLister.EndpointSlices(namespace).List(selector)
+}
+
+// endpointAddrs collects the addresses backing a set of SliceEndpoints, used
+// as the subset-churn cache key for the EndpointSlice path.
+func endpointAddrs(endpoints []resources.SliceEndpoint) sets.String {
+	addrs := sets.NewString()
+	for _, ep := range endpoints {
+		addrs.Insert(ep.Addresses...)
+	}
+	return addrs
+}
+
+// subsetEndpointSlices picks `n` distinct ready addresses out of `slices`,
+// consistently for `target` (the revision name), the same way subsetEndpoints
+// does for v1.Endpoints. Terminating endpoints are never selected. When
+// `preferZones` is non-empty, same-zone addresses are exhausted first before
+// falling back to the rest of the ring. It returns resources.SliceEndpoint
+// rather than a bare discoveryv1.Endpoint so the AddressType and Ports of the
+// originating slice travel along, which MakePublicEndpointSlices needs to
+// rebuild valid per-family slices.
+func subsetEndpointSlices(slices []*discoveryv1.EndpointSlice, target string, n int, preferZones sets.String) []resources.SliceEndpoint {
+	if n == 0 {
+		var all []resources.SliceEndpoint
+		for _, s := range slices {
+			for _, ep := range s.Endpoints {
+				if endpointSliceReady(ep.Conditions) {
+					all = append(all, resources.SliceEndpoint{Endpoint: ep, AddressType: s.AddressType, Ports: s.Ports})
+				}
+			}
+		}
+		return all
+	}
+
+	addrs := sets.NewString()
+	byAddr := map[string]resources.SliceEndpoint{}
+	for _, s := range slices {
+		for _, ep := range s.Endpoints {
+			if !endpointSliceReady(ep.Conditions) || len(ep.Addresses) == 0 {
+				continue
+			}
+			addr := ep.Addresses[0]
+			addrs.Insert(addr)
+			byAddr[addr] = resources.SliceEndpoint{Endpoint: ep, AddressType: s.AddressType, Ports: s.Ports}
+		}
+	}
+
+	if addrs.Len() <= n {
+		out := make([]resources.SliceEndpoint, 0, addrs.Len())
+		for _, a := range addrs.List() {
+			out = append(out, byAddr[a])
+		}
+		return out
+	}
+
+	// Partition into same-zone and other addresses so same-zone ones are
+	// preferred, then let hash.ChooseSubset do the stable, consistent pick
+	// within each partition.
+	local, rest := sets.NewString(), sets.NewString()
+	for _, a := range addrs.List() {
+		if ep := byAddr[a]; len(preferZones) > 0 && ep.Zone != nil && preferZones.Has(*ep.Zone) {
+			local.Insert(a)
+		} else {
+			rest.Insert(a)
+		}
+	}
+
+	var selection sets.String
+	if local.Len() >= n {
+		selection = hash.ChooseSubset(local, n, target)
+	} else {
+		selection = local
+		remaining := hash.ChooseSubset(rest, n-local.Len(), target)
+		selection = selection.Union(remaining)
+	}
+
+	out := make([]resources.SliceEndpoint, 0, selection.Len())
+	for _, a := range selection.List() {
+		out = append(out, byAddr[a])
+	}
+	return out
+}
+
+// subsetEndpointSlices picks sksKey's activator subset out of slices, either
+// via the legacy independent-per-revision hash.ChooseSubset (the package
+// level subsetEndpointSlices above), or, when enableBoundedLoadHashing is
+// set, via consistent hashing with bounded loads, mirroring the split
+// between subsetEndpoints and subsetEndpointsBoundedLoad on the v1.Endpoints
+// path. preferZones is only honored by the legacy path today; the ring built
+// by chooseBoundedLoadSubsetFor does not yet carry zone hints.
+func (r *reconciler) subsetEndpointSlices(ctx context.Context, slices []*discoveryv1.EndpointSlice, sksKey, target string, n int, preferZones sets.String) []resources.SliceEndpoint {
+	if !r.enableBoundedLoadHashing {
+		return subsetEndpointSlices(slices, target, n, preferZones)
+	}
+	if n == 0 {
+		return subsetEndpointSlices(slices, target, 0, nil)
+	}
+
+	addrs := sets.NewString()
+	byAddr := map[string]resources.SliceEndpoint{}
+	for _, s := range slices {
+		for _, ep := range s.Endpoints {
+			if !endpointSliceReady(ep.Conditions) || len(ep.Addresses) == 0 {
+				continue
+			}
+			addr := ep.Addresses[0]
+			addrs.Insert(addr)
+			byAddr[addr] = resources.SliceEndpoint{Endpoint: ep, AddressType: s.AddressType, Ports: s.Ports}
+		}
+	}
+	if addrs.Len() <= n {
+		out := make([]resources.SliceEndpoint, 0, addrs.Len())
+		for _, a := range addrs.List() {
+			out = append(out, byAddr[a])
+		}
+		return out
+	}
+
+	selection := r.chooseBoundedLoadSubsetFor(ctx, sksKey, target, n, addrs)
+	out := make([]resources.SliceEndpoint, 0, selection.Len())
+	for _, a := range selection.List() {
+		out = append(out, byAddr[a])
+	}
+	return out
+}
+
+// revisionZones collects the set of zones backing the private (ready) pods
+// of the revision, used to bias the activator subset towards the same zones.
+func revisionZones(pvtSlices []*discoveryv1.EndpointSlice) sets.String {
+	zones := sets.NewString()
+	for _, s := range pvtSlices {
+		for _, ep := range s.Endpoints {
+			if endpointSliceReady(ep.Conditions) && ep.Zone != nil {
+				zones.Insert(*ep.Zone)
+			}
+		}
+	}
+	return zones
+}
+
+// reconcilePublicEndpointSlices follows the same Serve/Proxy mode selection
+// as reconcilePublicEndpoints, but reads and writes discoveryv1.EndpointSlice
+// objects, which also lets it honor Terminating conditions, dual-stack
+// AddressTypes, and same-zone bias via Zone hints.
+func (r *reconciler) reconcilePublicEndpointSlices(ctx context.Context, sks *netv1alpha1.ServerlessService) error {
+	logger := logging.FromContext(ctx)
+
+	activatorSlices, err := r.listEndpointSlices(system.Namespace(), networking.ActivatorServiceName)
+	if err != nil {
+		return fmt.Errorf("failed to list activator endpoint slices: %w", err)
+	}
+
+	psn := sks.Status.PrivateServiceName
+	pvtSlices, err := r.listEndpointSlices(sks.Namespace, psn)
+	if err != nil {
+		return fmt.Errorf("failed to list private endpoint slices: %w", err)
+	}
+
+	pvtReady := 0
+	for _, s := range pvtSlices {
+		for _, ep := range s.Endpoints {
+			if endpointSliceReady(ep.Conditions) {
+				pvtReady += len(ep.Addresses)
+			}
+		}
+	}
+	sharedReady := 0
+	for _, s := range activatorSlices {
+		for _, ep := range s.Endpoints {
+			if endpointSliceReady(ep.Conditions) {
+				sharedReady += len(ep.Addresses)
+			}
+		}
+	}
+
+	logger.Infof("SKS is in %s mode (EndpointSlice path); has %d endpoints in %s; %d activator endpoints",
+		sks.Spec.Mode, pvtReady, psn, sharedReady)
+
+	foundServingEndpoints := false
+	mode := sks.Spec.Mode
+	if pvtReady == 0 {
+		if mode != netv1alpha1.SKSOperationModeProxy {
+			logger.Info("Forcing SKS into Proxy mode, insufficient ready endpoints.")
+			recordModeOverride(ctx, sks, mode, netv1alpha1.SKSOperationModeProxy, "InsufficientReadyEndpoints")
+		}
+		mode = netv1alpha1.SKSOperationModeProxy
+	} else {
+		foundServingEndpoints = true
+	}
+	if sharedReady == 0 {
+		if mode != netv1alpha1.SKSOperationModeServe {
+			logger.Info("Forcing SKS into Serve mode, no activator endpoints.")
+			recordModeOverride(ctx, sks, mode, netv1alpha1.SKSOperationModeServe, "NoActivatorEndpoints")
+		}
+		mode = netv1alpha1.SKSOperationModeServe
+	}
+
+	// Mixed mode: keep a fraction of the activator subset alongside the ready
+	// private pods, the same gradual-cutover behavior reconcilePublicEndpoints
+	// applies for the v1.Endpoints path. It only kicks in when both sources
+	// are actually usable; the Proxy/Serve overrides above already take
+	// precedence otherwise.
+	mixedFraction, useMixed := proxyFraction(sks)
+	useMixed = useMixed && mode == sks.Spec.Mode // don't mix during a forced override
+	useMixed = useMixed && pvtReady > 0 && sharedReady > 0
+
+	sksKey := sks.Namespace + "/" + sks.Name
+	var endpoints []resources.SliceEndpoint
+	switch {
+	case useMixed:
+		numMixed := int(math.Ceil(mixedFraction * float64(sks.Spec.NumActivators)))
+		activatorSubset := r.subsetEndpointSlices(ctx, activatorSlices, sksKey, sks.Name, numMixed, revisionZones(pvtSlices))
+		endpoints = mergeSliceEndpoints(subsetEndpointSlices(pvtSlices, sks.Name, 0, nil), activatorSubset)
+		r.recordSubsetChurnIfChanged(ctx, sks, sksKey, endpointAddrs(activatorSubset))
+		recordActivatorSubsetSize(ctx, len(activatorSubset))
+	case mode == netv1alpha1.SKSOperationModeServe:
+		endpoints = subsetEndpointSlices(pvtSlices, sks.Name, 0, nil)
+		r.clearSubsetChurnCache(sksKey)
+		recordActivatorSubsetSize(ctx, 0)
+	case mode == netv1alpha1.SKSOperationModeProxy:
+		endpoints = r.subsetEndpointSlices(ctx, activatorSlices, sksKey, sks.Name, int(sks.Spec.NumActivators), revisionZones(pvtSlices))
+		r.recordSubsetChurnIfChanged(ctx, sks, sksKey, endpointAddrs(endpoints))
+		recordActivatorSubsetSize(ctx, len(endpoints))
+	}
+	recordPublicEndpointCount(ctx, mode, len(endpoints))
+
+	wantSlices := resources.MakePublicEndpointSlices(sks, endpoints)
+	if err := r.syncPublicEndpointSlices(ctx, sks, wantSlices); err != nil {
+		return err
+	}
+
+	if foundServingEndpoints {
+		sks.Status.MarkEndpointsReady()
+	} else {
+		logger.Info("No ready endpoints backing revision")
+		sks.Status.MarkEndpointsNotReady("NoHealthyBackends")
+	}
+	// MarkActivatorEndpointsPopulated/Removed are booleans on the SKS status
+	// (an external API type we can't add a partial-state value to), so a
+	// mixed-mode fraction is surfaced via sks_activator_subset_size and the
+	// SubsetChurn Events recorded above instead of a third status value here.
+	if !foundServingEndpoints || sks.Spec.Mode == netv1alpha1.SKSOperationModeProxy || useMixed {
+		sks.Status.MarkActivatorEndpointsPopulated()
+	} else {
+		sks.Status.MarkActivatorEndpointsRemoved()
+	}
+
+	logger.Debug("Done reconciling public EndpointSlices")
+	return nil
+}
+
+// syncPublicEndpointSlices creates, updates or deletes the public
+// EndpointSlices owned by sks so that they match want exactly (by name).
+func (r *reconciler) syncPublicEndpointSlices(ctx context.Context, sks *netv1alpha1.ServerlessService, want []*discoveryv1.EndpointSlice) error {
+	have, err := r.listEndpointSlices(sks.Namespace, sks.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list public endpoint slices: %w", err)
+	}
+	haveByName := make(map[string]*discoveryv1.EndpointSlice, len(have))
+	for _, s := range have {
+		if !metav1.IsControlledBy(s, sks) {
+			recordOwnershipConflict(ctx, sks, "EndpointSlice", s.Name)
+			sks.Status.MarkEndpointsNotOwned("EndpointSlice", s.Name)
+			return fmt.Errorf("SKS: %s does not own EndpointSlice: %s", sks.Name, s.Name)
+		}
+		haveByName[s.Name] = s
+	}
+
+	wantByName := make(map[string]*discoveryv1.EndpointSlice, len(want))
+	for _, s := range want {
+		wantByName[s.Name] = s
+		if existing, ok := haveByName[s.Name]; !ok {
+			if _, err := r.kubeclient.DiscoveryV1().EndpointSlices(sks.Namespace).Create(ctx, s, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create public EndpointSlice: %w", err)
+			}
+		} else if !equality.Semantic.DeepEqual(existing.Endpoints, s.Endpoints) ||
+			!equality.Semantic.DeepEqual(existing.AddressType, s.AddressType) ||
+			!equality.Semantic.DeepEqual(existing.Ports, s.Ports) {
+			update := existing.DeepCopy()
+			update.Endpoints = s.Endpoints
+			update.AddressType = s.AddressType
+			update.Ports = s.Ports
+			if _, err := r.kubeclient.DiscoveryV1().EndpointSlices(sks.Namespace).Update(ctx, update, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("failed to update public EndpointSlice: %w", err)
+			}
+		}
+	}
+	for name, s := range haveByName {
+		if _, ok := wantByName[name]; !ok {
+			if err := r.kubeclient.DiscoveryV1().EndpointSlices(sks.Namespace).Delete(ctx, s.Name, metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return fmt.Errorf("failed to delete stale public EndpointSlice: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 func (r *reconciler) reconcilePrivateService(ctx context.Context, sks *netv1alpha1.ServerlessService) error {
 	logger := logging.FromContext(ctx)
 
@@ -325,6 +948,7 @@ func (r *reconciler) reconcilePrivateService(ctx context.Context, sks *netv1alph
 	} else if err != nil {
 		return fmt.Errorf("failed to get private K8s Service: %w", err)
 	} else if !metav1.IsControlledBy(svc, sks) {
+		recordOwnershipConflict(ctx, sks, "Service", svc.Name)
 		sks.Status.MarkEndpointsNotOwned("Service", svc.Name)
 		return fmt.Errorf("SKS: %s does not own Service: %s", sks.Name, svc.Name)
 	} else {